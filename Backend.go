@@ -0,0 +1,73 @@
+package database
+
+import (
+	"errors"
+	"os"
+)
+
+// Backend abstracts the driver used to talk to MongoDB, so that callers can choose between the
+// legacy gopkg.in/mgo.v2 driver and the official go.mongodb.org/mongo-driver without rewriting
+// every call site. Select one with NewBackend or MONGO_DRIVER/BackendFromEnv.
+type Backend interface {
+	DB(name string) DB
+	Ping() error
+	Close() error
+}
+
+// DB is the Backend-agnostic equivalent of a MongoDB database handle.
+type DB interface {
+	Collection(name string) Collection
+}
+
+// Collection is the Backend-agnostic equivalent of a MongoDB collection handle.
+type Collection interface {
+	Find(query interface{}) Query
+	Insert(docs ...interface{}) error
+}
+
+// Query is a not-yet-executed find operation, mirroring mgo's *Query.
+type Query interface {
+	One(result interface{}) error
+	Iter() Iter
+}
+
+// Iter walks the results of a Query, mirroring mgo's *Iter.
+type Iter interface {
+	Next(result interface{}) bool
+	Err() error
+	Close() error
+}
+
+// DriverMgo and DriverOfficial name the two supported Backend implementations.
+const (
+	DriverMgo      = "mgo"
+	DriverOfficial = "official"
+)
+
+// NewBackend dials MongoDB using cfg and wraps it in the Backend named by driver
+// (DriverMgo or DriverOfficial). An empty driver defaults to DriverMgo for compatibility with
+// existing callers.
+func NewBackend(driver string, cfg *Config) (Backend, error) {
+	switch driver {
+	case "", DriverMgo:
+		return newMgoBackend(cfg)
+	case DriverOfficial:
+		return newOfficialBackend(cfg)
+	default:
+		return nil, unsupportedDriverError(driver)
+	}
+}
+
+// BackendFromEnv calls NewBackend with cfg and the driver named by the MONGO_DRIVER environment
+// variable (default DriverMgo).
+func BackendFromEnv(cfg *Config) (Backend, error) {
+	return NewBackend(mongoDriver(), cfg)
+}
+
+func mongoDriver() string {
+	return os.Getenv("MONGO_DRIVER")
+}
+
+func unsupportedDriverError(driver string) error {
+	return errors.New("unsupported MONGO_DRIVER: " + driver)
+}
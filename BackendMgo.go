@@ -0,0 +1,78 @@
+package database
+
+import "gopkg.in/mgo.v2"
+
+// mgoBackend implements Backend on top of the existing Client/mgo.v2 connection logic, preserving
+// the package's current behavior.
+type mgoBackend struct {
+	client *Client
+}
+
+func newMgoBackend(cfg *Config) (Backend, error) {
+	client, err := Connect(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &mgoBackend{client: client}, nil
+}
+
+func (b *mgoBackend) DB(name string) DB {
+	return mgoDB{db: b.client.DB(name)}
+}
+
+func (b *mgoBackend) Ping() error {
+	return b.client.Ping()
+}
+
+func (b *mgoBackend) Close() error {
+	b.client.Close()
+	return nil
+}
+
+type mgoDB struct {
+	db *mgo.Database
+}
+
+func (d mgoDB) Collection(name string) Collection {
+	return mgoCollection{c: d.db.C(name)}
+}
+
+type mgoCollection struct {
+	c *mgo.Collection
+}
+
+func (c mgoCollection) Find(query interface{}) Query {
+	return mgoQuery{q: c.c.Find(query)}
+}
+
+func (c mgoCollection) Insert(docs ...interface{}) error {
+	return c.c.Insert(docs...)
+}
+
+type mgoQuery struct {
+	q *mgo.Query
+}
+
+func (q mgoQuery) One(result interface{}) error {
+	return q.q.One(result)
+}
+
+func (q mgoQuery) Iter() Iter {
+	return mgoIter{it: q.q.Iter()}
+}
+
+type mgoIter struct {
+	it *mgo.Iter
+}
+
+func (i mgoIter) Next(result interface{}) bool {
+	return i.it.Next(result)
+}
+
+func (i mgoIter) Err() error {
+	return i.it.Err()
+}
+
+func (i mgoIter) Close() error {
+	return i.it.Close()
+}
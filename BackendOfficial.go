@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// officialBackend implements Backend on top of go.mongodb.org/mongo-driver, giving access to
+// features mgo.v2 lacks (retryable writes, causal consistency, SCRAM-SHA-256, SRV). The Backend
+// interface stays synchronous and context-free to match mgo's style, so calls use
+// context.Background() internally.
+type officialBackend struct {
+	client *mongo.Client
+}
+
+// newOfficialBackend dials MongoDB via go.mongodb.org/mongo-driver. cfg.DialRetries,
+// cfg.HealthCheckInterval and the Reconnect* fields are not used here: the official driver handles
+// connection pooling, retries and server discovery itself, so there is no Supervisor to configure.
+func newOfficialBackend(cfg *Config) (Backend, error) {
+	clientOpts := officialClientOptions(cfg)
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, clientOpts)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, err
+	}
+
+	return &officialBackend{client: client}, nil
+}
+
+func officialClientOptions(cfg *Config) *options.ClientOptions {
+	if cfg.ConnectionString != "" {
+		return options.Client().ApplyURI(cfg.ConnectionString)
+	}
+
+	clientOpts := options.Client().
+		SetHosts(cfg.Addrs).
+		SetConnectTimeout(cfg.Timeout).
+		SetDirect(cfg.Direct)
+
+	if cfg.Username != "" || cfg.Password != "" {
+		clientOpts.SetAuth(options.Credential{
+			Username:   cfg.Username,
+			Password:   cfg.Password,
+			AuthSource: cfg.AuthSource,
+		})
+	}
+	if cfg.ReplicaSet != "" {
+		clientOpts.SetReplicaSet(cfg.ReplicaSet)
+	}
+	if cfg.PoolLimit > 0 {
+		clientOpts.SetMaxPoolSize(uint64(cfg.PoolLimit))
+	}
+	if cfg.TLSConfig != nil {
+		clientOpts.SetTLSConfig(cfg.TLSConfig)
+	}
+
+	return clientOpts
+}
+
+func (b *officialBackend) DB(name string) DB {
+	return officialDB{db: b.client.Database(name)}
+}
+
+func (b *officialBackend) Ping() error {
+	return b.client.Ping(context.Background(), nil)
+}
+
+func (b *officialBackend) Close() error {
+	return b.client.Disconnect(context.Background())
+}
+
+type officialDB struct {
+	db *mongo.Database
+}
+
+func (d officialDB) Collection(name string) Collection {
+	return officialCollection{c: d.db.Collection(name)}
+}
+
+type officialCollection struct {
+	c *mongo.Collection
+}
+
+func (c officialCollection) Find(query interface{}) Query {
+	return officialQuery{c: c.c, filter: query}
+}
+
+func (c officialCollection) Insert(docs ...interface{}) error {
+	_, err := c.c.InsertMany(context.Background(), docs)
+	return err
+}
+
+type officialQuery struct {
+	c      *mongo.Collection
+	filter interface{}
+}
+
+func (q officialQuery) One(result interface{}) error {
+	return q.c.FindOne(context.Background(), q.filter).Decode(result)
+}
+
+func (q officialQuery) Iter() Iter {
+	cursor, err := q.c.Find(context.Background(), q.filter)
+	return &officialIter{cursor: cursor, err: err}
+}
+
+// officialIter uses a pointer receiver so that a Decode failure in Next can be recorded and
+// later reported by Err, rather than being discarded.
+type officialIter struct {
+	cursor *mongo.Cursor
+	err    error
+}
+
+func (i *officialIter) Next(result interface{}) bool {
+	if i.err != nil || i.cursor == nil || !i.cursor.Next(context.Background()) {
+		return false
+	}
+	if err := i.cursor.Decode(result); err != nil {
+		i.err = err
+		return false
+	}
+	return true
+}
+
+func (i *officialIter) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	if i.cursor == nil {
+		return nil
+	}
+	return i.cursor.Err()
+}
+
+func (i *officialIter) Close() error {
+	if i.cursor == nil {
+		return i.err
+	}
+	return i.cursor.Close(context.Background())
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"os"
+	"testing"
+
+	"gopkg.in/mgo.v2"
+)
+
+func TestNewBackendRejectsUnknownDriver(t *testing.T) {
+	_, err := NewBackend("bogus", &Config{})
+	if err == nil {
+		t.Fatal("expected an error for an unknown driver")
+	}
+}
+
+func TestNewBackendDefaultsToMgo(t *testing.T) {
+	withFakeDialer(t, func(*mgo.DialInfo) (*mgo.Session, error) {
+		return &mgo.Session{}, nil
+	})
+
+	backend, err := NewBackend("", &Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := backend.(*mgoBackend); !ok {
+		t.Errorf("backend = %T, want *mgoBackend", backend)
+	}
+}
+
+func TestBackendFromEnvUsesMongoDriver(t *testing.T) {
+	t.Setenv("MONGO_DRIVER", "bogus")
+
+	if _, err := BackendFromEnv(&Config{}); err == nil {
+		t.Fatal("expected BackendFromEnv to surface the unsupported driver error")
+	}
+}
+
+func TestBackendFromEnvDefaultsToMgo(t *testing.T) {
+	os.Unsetenv("MONGO_DRIVER")
+	withFakeDialer(t, func(*mgo.DialInfo) (*mgo.Session, error) {
+		return &mgo.Session{}, nil
+	})
+
+	backend, err := BackendFromEnv(&Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := backend.(*mgoBackend); !ok {
+		t.Errorf("backend = %T, want *mgoBackend", backend)
+	}
+}
@@ -0,0 +1,232 @@
+package database
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"gopkg.in/mgo.v2"
+)
+
+// Config describes how to connect to a MongoDB deployment. It supersedes the
+// MONGO_* environment variables for callers that need more control, such as a
+// custom TLS configuration, multiple databases, or dial retries.
+type Config struct {
+	// ConnectionString, if set, takes precedence over the individual fields
+	// below. Connect and the DriverMgo Backend parse it with parseMongoURL; the
+	// DriverOfficial Backend passes it to the official driver's URI parser.
+	ConnectionString string
+
+	Addrs      []string
+	Username   string
+	Password   string
+	Database   string
+	ReplicaSet string
+	AuthSource string
+
+	// TLSConfig, if non-nil, enables TLS using the given configuration. This
+	// allows callers to supply a custom RootCAs pool or set InsecureSkipVerify
+	// for self-signed deployments.
+	TLSConfig *tls.Config
+
+	Timeout     time.Duration
+	PoolLimit   int
+	// DialRetries is only honored by the DriverMgo Backend/Connect; see the note on
+	// HealthCheckInterval below.
+	DialRetries int
+	Direct      bool
+
+	// HealthCheckInterval, if positive, starts a Supervisor goroutine that pings the session on
+	// this interval and reconnects it on failure. Zero disables the supervisor.
+	//
+	// This and the other resilience fields below (DialRetries, ReconnectBaseDelay,
+	// ReconnectMaxDelay, ReconnectMaxAttempts) are only implemented by Connect/the DriverMgo
+	// Backend. The DriverOfficial Backend (go.mongodb.org/mongo-driver) relies on that driver's
+	// own connection pooling and retry behavior instead, and silently ignores these fields.
+	HealthCheckInterval time.Duration
+
+	// ReconnectBaseDelay, ReconnectMaxDelay and ReconnectMaxAttempts tune the exponential backoff
+	// the Supervisor uses when redialing after a failed Ping/Refresh. ReconnectBaseDelay defaults
+	// to one second and ReconnectMaxAttempts of zero means retry forever. Not honored by the
+	// DriverOfficial Backend; see HealthCheckInterval above.
+	ReconnectBaseDelay   time.Duration
+	ReconnectMaxDelay    time.Duration
+	ReconnectMaxAttempts int
+}
+
+// Client wraps an *mgo.Session and the DialInfo it was created from. A Client created via Connect
+// with a positive Config.HealthCheckInterval also runs a Supervisor goroutine that keeps the
+// session alive across transient network blips and primary failovers.
+type Client struct {
+	cfg      *Config
+	dialInfo *mgo.DialInfo
+
+	mu      sync.RWMutex
+	session *mgo.Session
+	healthy bool
+	hooks   []func(*mgo.Session)
+
+	stop chan struct{}
+}
+
+// DB returns a handle to the named database, or to the database configured
+// via Config/the connection string when name is empty.
+func (c *Client) DB(name string) *mgo.Database {
+	if name == "" {
+		name = c.dialInfo.Database
+	}
+	return c.currentSession().DB(name)
+}
+
+// Ping checks that the underlying session is still reachable.
+func (c *Client) Ping() error {
+	return c.currentSession().Ping()
+}
+
+// Close stops the Supervisor goroutine, if any, and terminates the session.
+func (c *Client) Close() {
+	if c.stop != nil {
+		close(c.stop)
+	}
+	c.currentSession().Close()
+}
+
+func (c *Client) currentSession() *mgo.Session {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.session
+}
+
+// Connect dials MongoDB using cfg and returns a Client wrapping the resulting session. Unlike the
+// old package init(), it retries the initial dial (cfg.DialRetries) and returns an error instead
+// of panicking if every attempt fails. If cfg.HealthCheckInterval is positive, Connect also starts
+// a Supervisor goroutine to keep the session alive.
+func Connect(cfg *Config) (*Client, error) {
+	dialInfo, err := dialInfoFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect(cfg, dialInfo)
+}
+
+// connect dials dialInfo with retries and, if configured, starts the Supervisor. It is the shared
+// implementation behind Connect and ConnectFromEnv so both get the same resilience behavior.
+func connect(cfg *Config, dialInfo *mgo.DialInfo) (*Client, error) {
+	session, err := dialWithRetries(dialInfo, cfg.DialRetries, reconnectBaseDelay(cfg))
+	if err != nil {
+		return nil, err
+	}
+	session.SetMode(mgo.Monotonic, true)
+
+	client := &Client{
+		cfg:      cfg,
+		dialInfo: dialInfo,
+		session:  session,
+		healthy:  true,
+	}
+
+	if cfg.HealthCheckInterval > 0 {
+		client.stop = make(chan struct{})
+		go client.supervise()
+	}
+
+	return client, nil
+}
+
+// dialWithInfo is an indirection over mgo.DialWithInfo, so tests can substitute a fake dialer
+// instead of requiring a live MongoDB server.
+var dialWithInfo = mgo.DialWithInfo
+
+func dialWithRetries(dialInfo *mgo.DialInfo, retries int, baseDelay time.Duration) (*mgo.Session, error) {
+	var lastErr error
+	delay := baseDelay
+
+	for attempt := 0; attempt <= retries; attempt++ {
+		session, err := dialWithInfo(dialInfo)
+		if err == nil {
+			return session, nil
+		}
+		lastErr = err
+
+		if attempt < retries {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	return nil, lastErr
+}
+
+// defaultEnvDialRetries and defaultEnvHealthCheckInterval are the resilience settings
+// ConnectFromEnv applies since the MONGO_* variables have no way to configure them. Transient
+// DNS/TLS errors when talking to Atlas are common during container startup, so env-var callers
+// get the same retry/Supervisor behavior as Connect instead of failing on the first dial.
+const (
+	defaultEnvDialRetries         = 3
+	defaultEnvHealthCheckInterval = 30 * time.Second
+)
+
+// ConnectFromEnv reproduces the historical MONGO_* environment variable
+// behavior described in the package doc comment, for callers upgrading from
+// the old init()-based API. It returns an error rather than calling
+// log.Panic/log.Fatal, retries the initial dial, and starts a Supervisor.
+func ConnectFromEnv() (*Client, error) {
+	mongo := mongoConnectionString()
+
+	var dialInfo *mgo.DialInfo
+	if mongo == "" {
+		dialInfo = getDialInfoParameters()
+	} else {
+		var err error
+		dialInfo, err = parseMongoURL(mongo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cfg := &Config{
+		DialRetries:         defaultEnvDialRetries,
+		HealthCheckInterval: defaultEnvHealthCheckInterval,
+	}
+
+	return connect(cfg, dialInfo)
+}
+
+func dialInfoFromConfig(cfg *Config) (*mgo.DialInfo, error) {
+	if cfg.ConnectionString != "" {
+		return parseMongoURL(cfg.ConnectionString)
+	}
+
+	dialInfo := &mgo.DialInfo{
+		Addrs:          cfg.Addrs,
+		Database:       cfg.Database,
+		Username:       cfg.Username,
+		Password:       cfg.Password,
+		ReplicaSetName: cfg.ReplicaSet,
+		Source:         cfg.AuthSource,
+		Timeout:        cfg.Timeout,
+		PoolLimit:      cfg.PoolLimit,
+		Direct:         cfg.Direct,
+	}
+
+	if cfg.TLSConfig != nil {
+		tlsConfig := cfg.TLSConfig
+		dialInfo.DialServer = func(addr *mgo.ServerAddr) (net.Conn, error) {
+			return tls.Dial("tcp", addr.String(), tlsConfig)
+		}
+	}
+
+	return dialInfo, nil
+}
+
+// defaultReconnectBaseDelay is used whenever a Config doesn't specify ReconnectBaseDelay.
+const defaultReconnectBaseDelay = time.Second
+
+func reconnectBaseDelay(cfg *Config) time.Duration {
+	if cfg.ReconnectBaseDelay > 0 {
+		return cfg.ReconnectBaseDelay
+	}
+	return defaultReconnectBaseDelay
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gopkg.in/mgo.v2"
+)
+
+// withFakeDialer swaps dialWithInfo for dial and restores the original on test cleanup.
+func withFakeDialer(t *testing.T, dial func(*mgo.DialInfo) (*mgo.Session, error)) {
+	t.Helper()
+	original := dialWithInfo
+	dialWithInfo = dial
+	t.Cleanup(func() { dialWithInfo = original })
+}
+
+func TestDialWithRetriesSucceedsImmediately(t *testing.T) {
+	calls := 0
+	withFakeDialer(t, func(*mgo.DialInfo) (*mgo.Session, error) {
+		calls++
+		return &mgo.Session{}, nil
+	})
+
+	if _, err := dialWithRetries(&mgo.DialInfo{}, 3, time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestDialWithRetriesSucceedsAfterFailures(t *testing.T) {
+	calls := 0
+	withFakeDialer(t, func(*mgo.DialInfo) (*mgo.Session, error) {
+		calls++
+		if calls < 3 {
+			return nil, errors.New("connection refused")
+		}
+		return &mgo.Session{}, nil
+	})
+
+	if _, err := dialWithRetries(&mgo.DialInfo{}, 3, time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestDialWithRetriesExhaustsRetries(t *testing.T) {
+	calls := 0
+	wantErr := errors.New("connection refused")
+	withFakeDialer(t, func(*mgo.DialInfo) (*mgo.Session, error) {
+		calls++
+		return nil, wantErr
+	})
+
+	_, err := dialWithRetries(&mgo.DialInfo{}, 2, time.Millisecond)
+	if err != wantErr {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3 (1 initial + 2 retries)", calls)
+	}
+}
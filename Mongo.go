@@ -1,8 +1,13 @@
 /*
-Package database provides an easy mechanism to allow an application to create a <ongo DB Connection as the application
+Package database provides an easy mechanism to allow an application to create a mongo DB Connection as the application
 starts up.
 
-The package looks for Environment Parameters and allows for either a connection string or individual elements.
+Use Connect with a *Config to dial MongoDB explicitly, including custom TLS settings (RootCAs,
+InsecureSkipVerify), multiple databases, or pool/retry tuning.
+
+For applications upgrading from the old init()-based package, ConnectFromEnv reproduces the
+historical Environment Parameter behavior and allows for either a connection string or individual
+elements.
 
 First, it looks to see if the MONGO environment variable is set. The MONGO Environment variable, if set, should contain
 a mongo conneciton string, for example
@@ -17,14 +22,21 @@ If the MONGO environemnt variable is not set, the code moves onto the individual
 * MONGO_AUTH_SOURCE - Auth source
 * MONGO_SSL - Boolean indicate SSL
 
+BackendFromEnv reads MONGO_DRIVER ("mgo" or "official") to choose between the
+gopkg.in/mgo.v2 Backend and the go.mongodb.org/mongo-driver Backend; it defaults to "mgo" for
+backward compatibility. It connects using the *Config passed to it; the MONGO_* variables listed
+above are not read by BackendFromEnv.
+
  */
 package database
 
 import (
 	"gopkg.in/mgo.v2"
-	"log"
 	"net"
+	"os"
 	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
 	"net/url"
 	"strings"
 	"time"
@@ -32,32 +44,46 @@ import (
 	"errors"
 )
 
-var Database *mgo.Database
+// mongoConnectionString returns the MONGO environment variable, which if set holds a full mongo
+// connection string and takes precedence over the individual MONGO_* variables below.
+func mongoConnectionString() string {
+	return os.Getenv("MONGO")
+}
 
-func init() {
-	log.Println("Starting Database")
+// mongoServers returns the comma-separated list of servers from MONGO_SERVERS.
+func mongoServers() []string {
+	servers := os.Getenv("MONGO_SERVERS")
+	if servers == "" {
+		return nil
+	}
+	return strings.Split(servers, ",")
+}
 
-	mongo :=mongoConnectionString()
+func mongoDB() string {
+	return os.Getenv("MONGO_DATABASE")
+}
 
-	var dialinfo *mgo.DialInfo
+func mongoUser() string {
+	return os.Getenv("MONGO_USER")
+}
 
-	if mongo == "" {
-		dialinfo = getDialInfoParameters()
-	} else {
-		var err error
-		dialinfo, err = parseMongoURL(mongo)
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
-	session, err := mgo.DialWithInfo(dialinfo)
-	if err != nil {
-		log.Panic(err)
-	}
-	session.SetMode(mgo.Monotonic, true)
+func mongoPassword() string {
+	return os.Getenv("MONGO_PASSWORD")
+}
+
+func mongoReplicaSet() string {
+	return os.Getenv("MONGO_REPLICA_SET")
+}
 
-	Database = session.DB(dialinfo.Database)
+func mongoAuthSource() string {
+	return os.Getenv("MONGO_AUTH_SOURCE")
+}
 
+// mongoSSL reports whether MONGO_SSL is set to a truthy value. An unset or unparseable value is
+// treated as false.
+func mongoSSL() bool {
+	ssl, _ := strconv.ParseBool(os.Getenv("MONGO_SSL"))
+	return ssl
 }
 
 func getDialInfoParameters() *mgo.DialInfo{
@@ -79,12 +105,30 @@ func getDialInfoParameters() *mgo.DialInfo{
 	return &dialinfo
 }
 
+// tlsOptions accumulates the various TLS-related query parameters seen while
+// walking the connection string, so that the *tls.Config can be built once
+// all of them have been read.
+type tlsOptions struct {
+	enabled     bool
+	insecure    bool
+	caFile      string
+	certKeyFile string
+}
+
 func parseMongoURL(rawURL string) (*mgo.DialInfo, error) {
 	url, err := url.Parse(rawURL)
 	if err != nil {
 		return nil, err
 	}
 
+	if url.Scheme == "mongodb+srv" {
+		resolved, err := parseMongoSRV(url)
+		if err != nil {
+			return nil, err
+		}
+		return parseMongoURL(resolved)
+	}
+
 	info := mgo.DialInfo{
 		Addrs:    strings.Split(url.Host, ","),
 		Database: strings.TrimPrefix(url.Path, "/"),
@@ -96,6 +140,8 @@ func parseMongoURL(rawURL string) (*mgo.DialInfo, error) {
 		info.Password, _ = url.User.Password()
 	}
 
+	var tlsOpts tlsOptions
+
 	query := url.Query()
 	for key, values := range query {
 		var value string
@@ -118,18 +164,41 @@ func parseMongoURL(rawURL string) (*mgo.DialInfo, error) {
 				return nil, errors.New("bad value for maxPoolSize: " + value)
 			}
 			info.PoolLimit = poolLimit
-		case "ssl":
-			// Unfortunately, mgo doesn't support the ssl parameter in its MongoDB URI parsing logic, so we have to handle that
-			// ourselves. See https://github.com/go-mgo/mgo/issues/84
-			ssl, err := strconv.ParseBool(value)
+		case "ssl", "tls":
+			// Unfortunately, mgo doesn't support the ssl parameter in its MongoDB URI parsing logic, so we have to handle
+			// ssl/tls ourselves. See https://github.com/go-mgo/mgo/issues/84. Atlas and the official driver use "tls"
+			// rather than "ssl", so both are accepted as equivalents.
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, errors.New("bad value for " + key + ": " + value)
+			}
+			tlsOpts.enabled = tlsOpts.enabled || enabled
+		case "tlsInsecure":
+			insecure, err := strconv.ParseBool(value)
 			if err != nil {
-				return nil, errors.New("bad value for ssl: " + value)
+				return nil, errors.New("bad value for tlsInsecure: " + value)
 			}
-			if ssl {
-				info.DialServer = func(addr *mgo.ServerAddr) (net.Conn, error) {
-					return tls.Dial("tcp", addr.String(), &tls.Config{})
-				}
+			tlsOpts.insecure = insecure
+		case "tlsCAFile":
+			tlsOpts.caFile = value
+		case "tlsCertificateKeyFile":
+			tlsOpts.certKeyFile = value
+		case "connectTimeoutMS":
+			timeoutMS, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, errors.New("bad value for connectTimeoutMS: " + value)
+			}
+			info.Timeout = time.Duration(timeoutMS) * time.Millisecond
+		case "socketTimeoutMS", "serverSelectionTimeoutMS":
+			// mgo.DialInfo has no equivalent of these timeouts; accept and ignore them rather than
+			// rejecting connection strings that set them, since they're common with Atlas.
+			if _, err := strconv.Atoi(value); err != nil {
+				return nil, errors.New("bad value for " + key + ": " + value)
 			}
+		case "w", "journal", "readPreference", "retryWrites", "retryReads":
+			// Write concern, read preference, and retryable writes/reads are applied per-session
+			// in mgo, not via DialInfo. Atlas appends retryWrites/retryReads to every connection
+			// string it generates, so these must be accepted rather than rejected.
 		case "connect":
 			if value == "direct" {
 				info.Direct = true
@@ -144,5 +213,43 @@ func parseMongoURL(rawURL string) (*mgo.DialInfo, error) {
 		}
 	}
 
+	if tlsOpts.enabled {
+		dialServer, err := tlsDialServer(tlsOpts)
+		if err != nil {
+			return nil, err
+		}
+		info.DialServer = dialServer
+	}
+
 	return &info, nil
 }
+
+// tlsDialServer builds the DialInfo.DialServer closure for a TLS-enabled connection, loading the
+// CA file and/or client certificate/key named in opts.
+func tlsDialServer(opts tlsOptions) (func(addr *mgo.ServerAddr) (net.Conn, error), error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: opts.insecure}
+
+	if opts.caFile != "" {
+		caCert, err := ioutil.ReadFile(opts.caFile)
+		if err != nil {
+			return nil, errors.New("failed to read tlsCAFile: " + err.Error())
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("failed to parse tlsCAFile: " + opts.caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.certKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.certKeyFile, opts.certKeyFile)
+		if err != nil {
+			return nil, errors.New("failed to load tlsCertificateKeyFile: " + err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return func(addr *mgo.ServerAddr) (net.Conn, error) {
+		return tls.Dial("tcp", addr.String(), tlsConfig)
+	}, nil
+}
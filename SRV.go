@@ -0,0 +1,73 @@
+package database
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// lookupSRV and lookupTXT are indirections over the net package DNS lookups used by
+// parseMongoSRV, so tests can substitute fake resolvers instead of hitting real DNS.
+var (
+	lookupSRV = net.LookupSRV
+	lookupTXT = net.LookupTXT
+)
+
+// parseMongoSRV resolves a mongodb+srv:// URL into an equivalent mongodb:// URL with the seed
+// list and default query options filled in, per the MongoDB SRV connection format:
+// https://docs.mongodb.com/manual/reference/connection-string/#dns-seedlist-connection-format
+//
+// It performs a DNS SRV lookup on _mongodb._tcp.<host> to discover the replica-set members, and a
+// TXT lookup on <host> to pick up default options such as replicaSet and authSource. TLS is
+// implicitly enabled for SRV connections unless the caller's query string already says otherwise.
+func parseMongoSRV(u *url.URL) (string, error) {
+	host := u.Hostname()
+
+	_, srvRecords, err := lookupSRV("mongodb", "tcp", host)
+	if err != nil {
+		return "", errors.New("failed to resolve SRV records for " + host + ": " + err.Error())
+	}
+	if len(srvRecords) == 0 {
+		return "", errors.New("no SRV records found for " + host)
+	}
+
+	addrs := make([]string, 0, len(srvRecords))
+	for _, record := range srvRecords {
+		target := strings.TrimSuffix(record.Target, ".")
+		addrs = append(addrs, target+":"+strconv.Itoa(int(record.Port)))
+	}
+
+	query := u.Query()
+
+	if txtRecords, err := lookupTXT(host); err == nil {
+		for _, txt := range txtRecords {
+			for _, pair := range strings.Split(txt, "&") {
+				kv := strings.SplitN(pair, "=", 2)
+				if len(kv) != 2 {
+					continue
+				}
+				if _, overridden := query[kv[0]]; !overridden {
+					query.Set(kv[0], kv[1])
+				}
+			}
+		}
+	}
+
+	if _, ok := query["ssl"]; !ok {
+		if _, ok := query["tls"]; !ok {
+			query.Set("tls", "true")
+		}
+	}
+
+	resolved := url.URL{
+		Scheme:   "mongodb",
+		User:     u.User,
+		Host:     strings.Join(addrs, ","),
+		Path:     u.Path,
+		RawQuery: query.Encode(),
+	}
+
+	return resolved.String(), nil
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+)
+
+func withFakeSRVResolvers(t *testing.T, srvRecords []*net.SRV, srvErr error, txtRecords []string, txtErr error) {
+	t.Helper()
+
+	origSRV, origTXT := lookupSRV, lookupTXT
+	lookupSRV = func(service, proto, name string) (string, []*net.SRV, error) {
+		return "", srvRecords, srvErr
+	}
+	lookupTXT = func(name string) ([]string, error) {
+		return txtRecords, txtErr
+	}
+	t.Cleanup(func() {
+		lookupSRV = origSRV
+		lookupTXT = origTXT
+	})
+}
+
+func TestParseMongoSRVExpandsSeedListAndEnablesTLS(t *testing.T) {
+	withFakeSRVResolvers(t,
+		[]*net.SRV{
+			{Target: "db1.example.net.", Port: 27017},
+			{Target: "db2.example.net.", Port: 27017},
+		}, nil,
+		nil, errors.New("no TXT records"),
+	)
+
+	u, err := url.Parse("mongodb+srv://cluster0.example.net/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := parseMongoSRV(u)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resolvedURL, err := url.Parse(resolved)
+	if err != nil {
+		t.Fatalf("resolved URL %q does not parse: %v", resolved, err)
+	}
+
+	if resolvedURL.Scheme != "mongodb" {
+		t.Errorf("scheme = %q, want mongodb", resolvedURL.Scheme)
+	}
+	if want := "db1.example.net:27017,db2.example.net:27017"; resolvedURL.Host != want {
+		t.Errorf("host = %q, want %q", resolvedURL.Host, want)
+	}
+	if tls := resolvedURL.Query().Get("tls"); tls != "true" {
+		t.Errorf("tls = %q, want %q (SRV should imply TLS by default)", tls, "true")
+	}
+}
+
+func TestParseMongoSRVTXTOptionsDoNotOverrideExplicitQuery(t *testing.T) {
+	withFakeSRVResolvers(t,
+		[]*net.SRV{{Target: "db1.example.net.", Port: 27017}}, nil,
+		[]string{"replicaSet=fromTXT&authSource=fromTXT"}, nil,
+	)
+
+	u, err := url.Parse("mongodb+srv://cluster0.example.net/app?replicaSet=explicit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := parseMongoSRV(u)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resolvedURL, err := url.Parse(resolved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := resolvedURL.Query()
+	if got := query.Get("replicaSet"); got != "explicit" {
+		t.Errorf("replicaSet = %q, want explicit value to win over TXT default", got)
+	}
+	if got := query.Get("authSource"); got != "fromTXT" {
+		t.Errorf("authSource = %q, want TXT default to be merged in", got)
+	}
+}
+
+func TestParseMongoSRVExplicitTLSFalseIsPreserved(t *testing.T) {
+	withFakeSRVResolvers(t,
+		[]*net.SRV{{Target: "db1.example.net.", Port: 27017}}, nil,
+		nil, errors.New("no TXT records"),
+	)
+
+	u, err := url.Parse("mongodb+srv://cluster0.example.net/app?tls=false")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := parseMongoSRV(u)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resolvedURL, err := url.Parse(resolved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resolvedURL.Query().Get("tls"); got != "false" {
+		t.Errorf("tls = %q, want the caller's explicit tls=false to be preserved", got)
+	}
+}
+
+func TestParseMongoSRVNoRecordsIsAnError(t *testing.T) {
+	withFakeSRVResolvers(t, nil, nil, nil, errors.New("no TXT records"))
+
+	u, err := url.Parse("mongodb+srv://cluster0.example.net/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parseMongoSRV(u); err == nil {
+		t.Fatal("expected an error when no SRV records are found")
+	}
+}
+
+func TestParseMongoSRVLookupFailureIsAnError(t *testing.T) {
+	withFakeSRVResolvers(t, nil, errors.New("dns failure"), nil, nil)
+
+	u, err := url.Parse("mongodb+srv://cluster0.example.net/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parseMongoSRV(u); err == nil {
+		t.Fatal("expected an error when the SRV lookup fails")
+	}
+}
+
+func TestParseMongoURLDispatchesSRVScheme(t *testing.T) {
+	withFakeSRVResolvers(t,
+		[]*net.SRV{{Target: "db1.example.net.", Port: 27017}}, nil,
+		nil, errors.New("no TXT records"),
+	)
+
+	info, err := parseMongoURL("mongodb+srv://cluster0.example.net/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(info.Addrs) != 1 || info.Addrs[0] != "db1.example.net:27017" {
+		t.Errorf("Addrs = %v, want [db1.example.net:27017]", info.Addrs)
+	}
+	if info.DialServer == nil {
+		t.Error("expected DialServer to be set since SRV implies TLS")
+	}
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2"
+)
+
+// OnReconnect registers a hook that is called with the new *mgo.Session whenever the Supervisor
+// redials after a lost connection. Callers typically use this to rebuild collection handles that
+// were obtained from a previous session.
+func (c *Client) OnReconnect(hook func(*mgo.Session)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks = append(c.hooks, hook)
+}
+
+// IsHealthy reports whether the most recent Ping (or reconnect) succeeded. It is only meaningful
+// once a Supervisor is running; a Client without one always reports the state of its last dial.
+func (c *Client) IsHealthy() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.healthy
+}
+
+// supervise periodically pings the session and repairs it on failure. It runs until c.stop is
+// closed by Close.
+func (c *Client) supervise() {
+	ticker := time.NewTicker(c.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.checkHealth()
+		}
+	}
+}
+
+// checkHealth pings the current session, attempting a cheap Refresh before falling back to a full
+// reconnect.
+func (c *Client) checkHealth() {
+	session := c.currentSession()
+
+	if err := session.Ping(); err == nil {
+		c.setHealthy(true)
+		return
+	}
+
+	session.Refresh()
+	if err := session.Ping(); err == nil {
+		c.setHealthy(true)
+		return
+	}
+
+	c.setHealthy(false)
+	c.reconnect()
+}
+
+// reconnect redials using the Client's original DialInfo with exponential backoff, retrying until
+// it succeeds, c.cfg.ReconnectMaxAttempts is exhausted, or the Client is closed.
+func (c *Client) reconnect() {
+	delay := reconnectBaseDelay(c.cfg)
+
+	for attempt := 1; c.cfg.ReconnectMaxAttempts == 0 || attempt <= c.cfg.ReconnectMaxAttempts; attempt++ {
+		session, err := dialWithInfo(c.dialInfo)
+		if err == nil {
+			session.SetMode(mgo.Monotonic, true)
+			c.swapSession(session)
+			return
+		}
+
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if c.cfg.ReconnectMaxDelay > 0 && delay > c.cfg.ReconnectMaxDelay {
+			delay = c.cfg.ReconnectMaxDelay
+		}
+	}
+}
+
+// swapSession installs a freshly dialed session, marks the Client healthy again, and runs the
+// OnReconnect hooks with the new session.
+func (c *Client) swapSession(session *mgo.Session) {
+	c.mu.Lock()
+	old := c.session
+	c.session = session
+	c.healthy = true
+	hooks := append([]func(*mgo.Session){}, c.hooks...)
+	c.mu.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+
+	for _, hook := range hooks {
+		hook(session)
+	}
+}
+
+func (c *Client) setHealthy(healthy bool) {
+	c.mu.Lock()
+	c.healthy = healthy
+	c.mu.Unlock()
+}
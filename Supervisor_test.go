@@ -0,0 +1,85 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gopkg.in/mgo.v2"
+)
+
+func newTestClient(cfg *Config) *Client {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	return &Client{
+		cfg:      cfg,
+		dialInfo: &mgo.DialInfo{},
+		stop:     make(chan struct{}),
+	}
+}
+
+func TestReconnectRedialsOnSuccess(t *testing.T) {
+	calls := 0
+	withFakeDialer(t, func(*mgo.DialInfo) (*mgo.Session, error) {
+		calls++
+		return &mgo.Session{}, nil
+	})
+
+	c := newTestClient(&Config{ReconnectBaseDelay: time.Millisecond})
+	c.reconnect()
+
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	if c.currentSession() == nil {
+		t.Error("expected reconnect to install a new session")
+	}
+	if !c.IsHealthy() {
+		t.Error("expected client to be healthy after a successful reconnect")
+	}
+}
+
+func TestReconnectStopsAtMaxAttempts(t *testing.T) {
+	calls := 0
+	withFakeDialer(t, func(*mgo.DialInfo) (*mgo.Session, error) {
+		calls++
+		return nil, errors.New("connection refused")
+	})
+
+	c := newTestClient(&Config{
+		ReconnectBaseDelay:   time.Millisecond,
+		ReconnectMaxDelay:    2 * time.Millisecond,
+		ReconnectMaxAttempts: 3,
+	})
+	c.reconnect()
+
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if c.currentSession() != nil {
+		t.Error("expected no session to be installed when every dial attempt fails")
+	}
+}
+
+func TestSwapSessionRunsOnReconnectHooks(t *testing.T) {
+	c := newTestClient(nil)
+
+	var got *mgo.Session
+	c.OnReconnect(func(s *mgo.Session) {
+		got = s
+	})
+
+	fake := &mgo.Session{}
+	c.swapSession(fake)
+
+	if got != fake {
+		t.Error("expected OnReconnect hook to fire with the new session")
+	}
+	if !c.IsHealthy() {
+		t.Error("expected client to be healthy after swapSession")
+	}
+	if c.currentSession() != fake {
+		t.Error("expected currentSession to return the swapped-in session")
+	}
+}